@@ -0,0 +1,122 @@
+// Package agents defines named bundles of system prompt, model, and tool
+// scoping ("which vector stores and MCP toolgroups this agent is allowed to
+// touch") loaded from a user-editable config file, so a single demo process
+// can switch between, say, a RAG-only support agent and an MCP-only ops
+// agent without recompiling.
+//
+// Config is read from ~/.config/llamastack-demo/agents.yaml. The loader only
+// understands the JSON subset of YAML (a plain object literal), which is
+// enough for a flat list of agents and avoids pulling in a YAML dependency;
+// the file keeps the .yaml extension because JSON is valid YAML and the
+// format is meant to be hand-edited.
+package agents
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// Agent is a named bundle of everything needed to run a scoped conversation:
+// the model and system prompt to use, which tools it may call, and which
+// already-provisioned vector stores / MCP toolgroups it may reach. Tools are
+// opt-in: an agent with no VectorStoreIDs simply never gets RAG instructions,
+// and one with no MCPToolGroupIDs never gets MCP instructions, regardless of
+// what the process has provisioned elsewhere.
+type Agent struct {
+	Name            string   `json:"-"`
+	SystemPrompt    string   `json:"system_prompt"`
+	Model           string   `json:"model"`
+	Tools           []string `json:"tools,omitempty"`
+	VectorStoreIDs  []string `json:"vector_store_ids,omitempty"`
+	MCPToolGroupIDs []string `json:"mcp_toolgroup_ids,omitempty"`
+	MaxInferIters   int      `json:"max_infer_iters,omitempty"`
+}
+
+// PrimaryVectorStoreID returns the vector store this agent should use for
+// RAG, or "" if it isn't granted one.
+func (a *Agent) PrimaryVectorStoreID() string {
+	if len(a.VectorStoreIDs) == 0 {
+		return ""
+	}
+	return a.VectorStoreIDs[0]
+}
+
+// PrimaryMCPToolGroupID returns the MCP toolgroup this agent should use, or
+// "" if it isn't granted one.
+func (a *Agent) PrimaryMCPToolGroupID() string {
+	if len(a.MCPToolGroupIDs) == 0 {
+		return ""
+	}
+	return a.MCPToolGroupIDs[0]
+}
+
+// AllowsTool reports whether name is in this agent's tool allow-list.
+func (a *Agent) AllowsTool(name string) bool {
+	for _, tool := range a.Tools {
+		if tool == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Registry is the set of agents loaded from a config file, keyed by name.
+type Registry struct {
+	path   string
+	agents map[string]*Agent
+}
+
+// DefaultPath returns ~/.config/llamastack-demo/agents.yaml.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "llamastack-demo", "agents.yaml"), nil
+}
+
+// LoadRegistry reads the agent config at path. A missing file yields an
+// empty registry rather than an error, since agents.yaml is optional.
+func LoadRegistry(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &Registry{path: path, agents: make(map[string]*Agent)}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read agent config %s: %w", path, err)
+	}
+
+	var raw map[string]*Agent
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse agent config %s: %w", path, err)
+	}
+
+	for name, agent := range raw {
+		agent.Name = name
+		if agent.MaxInferIters == 0 {
+			agent.MaxInferIters = 5
+		}
+	}
+
+	return &Registry{path: path, agents: raw}, nil
+}
+
+// Get looks up an agent by name.
+func (r *Registry) Get(name string) (*Agent, bool) {
+	agent, ok := r.agents[name]
+	return agent, ok
+}
+
+// Names returns every agent name in the registry, sorted.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.agents))
+	for name := range r.agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
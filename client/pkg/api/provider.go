@@ -0,0 +1,70 @@
+// Package api defines the provider-agnostic surface this demo is meant to
+// eventually run conversations against, so that the same prompt could be
+// sent to LlamaStack, OpenAI, Anthropic, or Google Gemini without the
+// caller caring which one is on the other end of the wire.
+//
+// That goal isn't reached yet: LlamaStackClient (in the client package)
+// still calls the concrete llamastackclient SDK directly for every real
+// chat flow (SendMessage, SendMessageStream, tool-call resolution, RAG,
+// MCP), because resolving tool calls needs richer request/response shapes
+// than ChatRequest/ChatResponse model today. Only provider *selection* —
+// picking which backend's ListModels to call via --provider or
+// LLM_PROVIDER — actually goes through this interface right now; see
+// newProvider and cmdModels in client/test_client.go.
+package api
+
+import "context"
+
+// ChatRequest is a single turn sent to a ChatProvider. Instructions carries
+// the system prompt for the turn (RAG/MCP guidance, an agent's
+// SystemPrompt, etc.); PreviousResponseID chains a turn onto an earlier one
+// for providers that support server-side conversation state.
+type ChatRequest struct {
+	Model              string
+	Instructions       string
+	Message            string
+	PreviousResponseID string
+}
+
+// ChatResponse is a provider's full reply to a ChatRequest.
+type ChatResponse struct {
+	ID               string
+	Text             string
+	PromptTokens     int64
+	CompletionTokens int64
+}
+
+// ChatStreamChunk is one incremental piece of a streamed reply. DeltaText
+// and ToolCallDelta are mutually exclusive per chunk; the token counts and
+// FinishReason are only set on the final chunk.
+type ChatStreamChunk struct {
+	DeltaText        string
+	ToolCallDelta    string
+	PromptTokens     int64
+	CompletionTokens int64
+	FinishReason     string
+}
+
+// ToolDefinition describes a tool a ChatProvider should make callable by the
+// model. MCPEndpoint is set for remote MCP tool groups and left empty for
+// tools the caller executes itself via a function-call round trip.
+type ToolDefinition struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+	MCPEndpoint string
+}
+
+// ChatProvider is the backend every supported LLM service implements, so
+// that the rest of the demo can be written once against the interface
+// instead of against any one vendor's client.
+type ChatProvider interface {
+	// ListModels returns the identifiers of every model this provider can serve.
+	ListModels(ctx context.Context) ([]string, error)
+	// CreateResponse sends req and blocks until the full reply is ready.
+	CreateResponse(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+	// StreamResponse is the streaming counterpart to CreateResponse.
+	StreamResponse(ctx context.Context, req ChatRequest) (<-chan ChatStreamChunk, error)
+	// RegisterTool makes a tool available for the model to call.
+	RegisterTool(ctx context.Context, tool ToolDefinition) error
+}
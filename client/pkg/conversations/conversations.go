@@ -0,0 +1,227 @@
+// Package conversations persists chat turns to a JSON directory so that a
+// session can be left and resumed later, listed, inspected, or branched.
+//
+// Each conversation is stored as a single `<id>.json` file. Messages within a
+// conversation form a tree keyed by parent message ID: editing or replying to
+// an earlier turn does not overwrite history, it grows a new branch from that
+// point. HeadID always points at the tip of the currently active branch.
+package conversations
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Message is a single user or assistant turn, linked to its parent turn so
+// that branches can be reconstructed by walking ParentID back to the root.
+type Message struct {
+	ID             string    `json:"id"`
+	ParentID       string    `json:"parent_id,omitempty"`
+	Role           string    `json:"role"`
+	Content        string    `json:"content"`
+	AgentID        string    `json:"agent_id,omitempty"`
+	SessionID      string    `json:"session_id,omitempty"`
+	VectorStoreID  string    `json:"vector_store_id,omitempty"`
+	MCPToolGroupID string    `json:"mcp_tool_group_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// Conversation is a named tree of messages plus the ID of the currently
+// active leaf (HeadID). Resuming a conversation means restoring HeadID and
+// appending new messages below it.
+type Conversation struct {
+	ID        string              `json:"id"`
+	Title     string              `json:"title,omitempty"`
+	CreatedAt time.Time           `json:"created_at"`
+	HeadID    string              `json:"head_id,omitempty"`
+	Messages  map[string]*Message `json:"messages"`
+}
+
+// History walks the conversation from HeadID back to the root and returns the
+// messages in root-to-leaf order, i.e. the active branch.
+func (c *Conversation) History() []*Message {
+	var reversed []*Message
+	for id := c.HeadID; id != ""; {
+		msg, ok := c.Messages[id]
+		if !ok {
+			break
+		}
+		reversed = append(reversed, msg)
+		id = msg.ParentID
+	}
+
+	history := make([]*Message, len(reversed))
+	for i, msg := range reversed {
+		history[len(reversed)-1-i] = msg
+	}
+	return history
+}
+
+// AppendMessage adds msg as a child of parentID (the empty string means "root
+// of the tree"), assigns it a fresh ID, advances HeadID to it, and returns the
+// stored message.
+func (c *Conversation) AppendMessage(parentID string, msg Message) *Message {
+	if c.Messages == nil {
+		c.Messages = make(map[string]*Message)
+	}
+
+	msg.ID = newID("m")
+	msg.ParentID = parentID
+	msg.CreatedAt = time.Now()
+
+	stored := msg
+	c.Messages[stored.ID] = &stored
+	c.HeadID = stored.ID
+	return &stored
+}
+
+// Store persists conversations as one JSON file per conversation inside dir.
+type Store struct {
+	dir string
+}
+
+// NewStore opens (creating if necessary) a conversation store rooted at dir.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create conversation store at %s: %w", dir, err)
+	}
+	return &Store{dir: dir}, nil
+}
+
+// DefaultDir returns the conversation store path under the user's config
+// directory: ~/.config/llamastack-demo/conversations.
+func DefaultDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".config", "llamastack-demo", "conversations"), nil
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+// New creates, persists, and returns an empty conversation.
+func (s *Store) New(title string) (*Conversation, error) {
+	conv := &Conversation{
+		ID:        newID("c"),
+		Title:     title,
+		CreatedAt: time.Now(),
+		Messages:  make(map[string]*Message),
+	}
+	if err := s.Save(conv); err != nil {
+		return nil, err
+	}
+	return conv, nil
+}
+
+// Load reads a conversation by ID.
+func (s *Store) Load(id string) (*Conversation, error) {
+	data, err := os.ReadFile(s.path(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load conversation %s: %w", id, err)
+	}
+
+	var conv Conversation
+	if err := json.Unmarshal(data, &conv); err != nil {
+		return nil, fmt.Errorf("failed to parse conversation %s: %w", id, err)
+	}
+	return &conv, nil
+}
+
+// Save writes conv to disk.
+func (s *Store) Save(conv *Conversation) error {
+	data, err := json.MarshalIndent(conv, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation %s: %w", conv.ID, err)
+	}
+	if err := os.WriteFile(s.path(conv.ID), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write conversation %s: %w", conv.ID, err)
+	}
+	return nil
+}
+
+// Delete removes a conversation permanently.
+func (s *Store) Delete(id string) error {
+	if err := os.Remove(s.path(id)); err != nil {
+		return fmt.Errorf("failed to delete conversation %s: %w", id, err)
+	}
+	return nil
+}
+
+// List returns every stored conversation, most recently created first.
+func (s *Store) List() ([]*Conversation, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read conversation store: %w", err)
+	}
+
+	var convs []*Conversation
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		id := entry.Name()[:len(entry.Name())-len(".json")]
+		conv, err := s.Load(id)
+		if err != nil {
+			continue
+		}
+		convs = append(convs, conv)
+	}
+
+	sort.Slice(convs, func(i, j int) bool {
+		return convs[i].CreatedAt.After(convs[j].CreatedAt)
+	})
+	return convs, nil
+}
+
+// Branch creates a new conversation whose history is a copy of conv's
+// ancestry up to and including fromMessageID, so that appending to the new
+// conversation edits/re-prompts from that point without touching conv.
+func (s *Store) Branch(conv *Conversation, fromMessageID string) (*Conversation, error) {
+	source, ok := conv.Messages[fromMessageID]
+	if !ok {
+		return nil, fmt.Errorf("message %s not found in conversation %s", fromMessageID, conv.ID)
+	}
+
+	var chain []*Message
+	for msg := source; msg != nil; {
+		chain = append(chain, msg)
+		if msg.ParentID == "" {
+			break
+		}
+		msg = conv.Messages[msg.ParentID]
+	}
+
+	branch := &Conversation{
+		ID:        newID("c"),
+		Title:     conv.Title + " (branch)",
+		CreatedAt: time.Now(),
+		Messages:  make(map[string]*Message),
+	}
+
+	parentID := ""
+	for i := len(chain) - 1; i >= 0; i-- {
+		parentID = branch.AppendMessage(parentID, *chain[i]).ID
+	}
+
+	if err := s.Save(branch); err != nil {
+		return nil, err
+	}
+	return branch, nil
+}
+
+func newID(prefix string) string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("%s-%d", prefix, time.Now().UnixNano())
+	}
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(buf))
+}
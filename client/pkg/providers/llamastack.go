@@ -0,0 +1,213 @@
+// Package providers holds one api.ChatProvider implementation per supported
+// backend. LlamaStackProvider is the only one backed by a real SDK in this
+// tree today; the others are scaffolding for backends this environment
+// cannot vendor a client for yet (see their doc comments).
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	llamastackclient "github.com/llamastack/llama-stack-client-go"
+	"github.com/llamastack/llama-stack-client-go/option"
+
+	"llama-stack-client/pkg/api"
+)
+
+// LlamaStackConfig holds everything that used to be hard-coded into
+// NewLlamaStackClient: where the LlamaStack server lives and which model to
+// default to when a request doesn't name one.
+type LlamaStackConfig struct {
+	BaseURL      string
+	APIKey       string
+	DefaultModel string // model used for chat turns and prompt-starter generation
+	AgentModel   string // model used for the server-side conversational agent
+}
+
+// DefaultLlamaStackConfig returns the config this demo has always run
+// against: a local LlamaStack server fronting Ollama.
+func DefaultLlamaStackConfig() LlamaStackConfig {
+	return LlamaStackConfig{
+		BaseURL:      "http://localhost:8321",
+		APIKey:       "none",
+		DefaultModel: "ollama/llama3.2:1b",
+		AgentModel:   "ollama/llama3.2:3b",
+	}
+}
+
+// LlamaStackProvider implements api.ChatProvider against a real LlamaStack
+// server via the Responses API: the only provider in this package backed by
+// a real SDK, and the only one whose CreateResponse/StreamResponse actually
+// talk to a server. It is used directly by cmdModels (client/test_client.go)
+// for provider selection; LlamaStackClient's own chat flow does not route
+// through it (see the package doc comment on pkg/api for why) and instead
+// calls the concrete llamastackclient SDK itself, reusing the same
+// underlying *llamastackclient.Client this type builds.
+type LlamaStackProvider struct {
+	client       *llamastackclient.Client
+	defaultModel string
+}
+
+// NewLlamaStackProvider builds a provider from cfg.
+func NewLlamaStackProvider(cfg LlamaStackConfig) *LlamaStackProvider {
+	client := llamastackclient.NewClient(
+		option.WithBaseURL(cfg.BaseURL),
+		option.WithAPIKey(cfg.APIKey),
+	)
+	return &LlamaStackProvider{client: &client, defaultModel: cfg.DefaultModel}
+}
+
+// Client exposes the underlying SDK client for the parts of the demo
+// (conversation persistence, agents, tool-call gating) that are still
+// written directly against LlamaStack's richer API rather than the
+// lowest-common-denominator ChatProvider interface.
+func (p *LlamaStackProvider) Client() *llamastackclient.Client {
+	return p.client
+}
+
+// ListModels implements api.ChatProvider.
+func (p *LlamaStackProvider) ListModels(ctx context.Context) ([]string, error) {
+	models, err := p.client.Models.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list models: %w", err)
+	}
+
+	ids := make([]string, 0, len(*models))
+	for _, model := range *models {
+		ids = append(ids, model.Identifier)
+	}
+	return ids, nil
+}
+
+// CreateResponse implements api.ChatProvider.
+func (p *LlamaStackProvider) CreateResponse(ctx context.Context, req api.ChatRequest) (*api.ChatResponse, error) {
+	model := req.Model
+	if model == "" {
+		model = p.defaultModel
+	}
+
+	params := llamastackclient.ResponseNewParams{
+		Model: model,
+		Input: llamastackclient.ResponseNewParamsInputUnion{OfString: llamastackclient.String(req.Message)},
+		Store: llamastackclient.Bool(true),
+	}
+	if req.Instructions != "" {
+		params.Instructions = llamastackclient.String(req.Instructions)
+	}
+	if req.PreviousResponseID != "" {
+		params.PreviousResponseID = llamastackclient.String(req.PreviousResponseID)
+	}
+
+	resp, err := p.client.Responses.New(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create response: %w", err)
+	}
+
+	full, err := p.client.Responses.Get(ctx, resp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve response: %w", err)
+	}
+
+	return &api.ChatResponse{ID: full.ID, Text: extractText(full)}, nil
+}
+
+// StreamResponse implements api.ChatProvider.
+func (p *LlamaStackProvider) StreamResponse(ctx context.Context, req api.ChatRequest) (<-chan api.ChatStreamChunk, error) {
+	model := req.Model
+	if model == "" {
+		model = p.defaultModel
+	}
+
+	params := llamastackclient.ResponseNewParams{
+		Model: model,
+		Input: llamastackclient.ResponseNewParamsInputUnion{OfString: llamastackclient.String(req.Message)},
+		Store: llamastackclient.Bool(true),
+	}
+	if req.Instructions != "" {
+		params.Instructions = llamastackclient.String(req.Instructions)
+	}
+	if req.PreviousResponseID != "" {
+		params.PreviousResponseID = llamastackclient.String(req.PreviousResponseID)
+	}
+
+	stream := p.client.Responses.NewStreaming(ctx, params)
+	chunks := make(chan api.ChatStreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+
+		for stream.Next() {
+			event := stream.Current()
+			switch event.Type {
+			case "response.output_text.delta":
+				chunks <- api.ChatStreamChunk{DeltaText: event.Delta}
+			case "response.function_call_arguments.delta", "response.mcp_call.arguments.delta":
+				chunks <- api.ChatStreamChunk{ToolCallDelta: event.Delta}
+			case "response.completed":
+				chunks <- api.ChatStreamChunk{
+					PromptTokens:     event.Response.Usage.InputTokens,
+					CompletionTokens: event.Response.Usage.OutputTokens,
+					FinishReason:     "stop",
+				}
+			case "response.failed", "response.incomplete":
+				chunks <- api.ChatStreamChunk{FinishReason: event.Type}
+			}
+		}
+		if err := stream.Err(); err != nil {
+			chunks <- api.ChatStreamChunk{FinishReason: fmt.Sprintf("error: %v", err)}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// RegisterTool implements api.ChatProvider. Only MCP tool groups are
+// supported today; a ToolDefinition without MCPEndpoint is a client-side
+// function tool, which LlamaStack's Responses API already handles without
+// pre-registration.
+func (p *LlamaStackProvider) RegisterTool(ctx context.Context, tool api.ToolDefinition) error {
+	if tool.MCPEndpoint == "" {
+		return nil
+	}
+
+	err := p.client.Toolgroups.Register(ctx, llamastackclient.ToolgroupRegisterParams{
+		ToolgroupID: tool.Name,
+		ProviderID:  "model-context-protocol",
+		McpEndpoint: llamastackclient.ToolgroupRegisterParamsMcpEndpoint{Uri: tool.MCPEndpoint},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to register MCP tool group %s: %w", tool.Name, err)
+	}
+	return nil
+}
+
+// extractText returns the first non-empty message text in resp's output, or
+// "" if none is found. Unlike LlamaStackClient.extractReplyText, it skips a
+// message item without readable text instead of reporting a placeholder.
+func extractText(resp *llamastackclient.ResponseObject) string {
+	for _, outputItem := range resp.Output {
+		if outputItem.Type != "message" {
+			continue
+		}
+		msg := outputItem.AsMessage()
+		if !msg.JSON.Content.Valid() {
+			continue
+		}
+
+		var contentItems []struct {
+			Text string `json:"text"`
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(msg.JSON.Content.Raw()), &contentItems); err != nil {
+			continue
+		}
+		for _, item := range contentItems {
+			if item.Type == "output_text" && item.Text != "" {
+				return item.Text
+			}
+		}
+	}
+	return ""
+}
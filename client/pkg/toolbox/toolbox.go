@@ -0,0 +1,312 @@
+// Package toolbox ships a small set of local filesystem and shell tools the
+// assistant can call directly, in-process, instead of requiring a separate
+// MCP server for simple file inspection and editing. It plays the same role
+// as SetupMCPToolGroup's remote toolgroup, but every tool runs as a plain Go
+// function: a Toolbox's specs are dispatched through the same
+// confirmation-gated toolExecutor as any other client-side function call.
+package toolbox
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxTreeDepth caps how deep dir_tree will recurse, so a call against a
+// large directory can't produce an unbounded response.
+const maxTreeDepth = 5
+
+// ToolSpec describes one callable tool: its name and JSON-schema-ish
+// Parameters as the model sees them, and Impl as the function that actually
+// runs it once a call has been approved.
+type ToolSpec struct {
+	Name        string
+	Description string
+	Parameters  map[string]any
+	Impl        func(argumentsJSON string) (string, error)
+}
+
+// Toolbox is a set of built-in tools sandboxed to Root: every path argument
+// is resolved relative to it and rejected if it would escape.
+type Toolbox struct {
+	// Root is the directory every tool's paths are resolved against.
+	Root string
+	// AllowedCommands is shell_exec's allow-list. A command not in this list
+	// is refused; the list is empty (shell_exec always refuses) by default.
+	AllowedCommands []string
+}
+
+// New returns a Toolbox sandboxed to root with shell_exec disabled; call
+// AllowCommand to opt specific commands into it.
+func New(root string) *Toolbox {
+	return &Toolbox{Root: root}
+}
+
+// AllowCommand adds name to shell_exec's allow-list.
+func (t *Toolbox) AllowCommand(name string) {
+	t.AllowedCommands = append(t.AllowedCommands, name)
+}
+
+// Specs returns every built-in tool, ready to dispatch from a toolExecutor
+// alongside a remote MCP toolgroup.
+func (t *Toolbox) Specs() []ToolSpec {
+	return []ToolSpec{
+		t.dirTreeSpec(),
+		t.readFileSpec(),
+		t.writeFileSpec(),
+		t.modifyFileSpec(),
+		t.shellExecSpec(),
+	}
+}
+
+// resolve sandboxes a user-supplied relative path to t.Root, rejecting
+// absolute paths and anything that would resolve outside the root.
+func (t *Toolbox) resolve(relPath string) (string, error) {
+	if filepath.IsAbs(relPath) {
+		return "", fmt.Errorf("path must be relative to the sandbox root, got %q", relPath)
+	}
+
+	rootAbs, err := filepath.Abs(t.Root)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve sandbox root: %w", err)
+	}
+	fullAbs, err := filepath.Abs(filepath.Join(t.Root, relPath))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %q: %w", relPath, err)
+	}
+
+	if fullAbs != rootAbs && !strings.HasPrefix(fullAbs, rootAbs+string(filepath.Separator)) {
+		return "", fmt.Errorf("path %q escapes sandbox root %q", relPath, t.Root)
+	}
+	return fullAbs, nil
+}
+
+func (t *Toolbox) dirTreeSpec() ToolSpec {
+	return ToolSpec{
+		Name:        "dir_tree",
+		Description: "List files and directories under a path (relative to the sandbox root), up to 5 levels deep.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{
+					"type":        "string",
+					"description": `Directory to list, relative to the sandbox root ("" for the root itself).`,
+				},
+			},
+		},
+		Impl: func(argumentsJSON string) (string, error) {
+			var args struct {
+				Path string `json:"path"`
+			}
+			if argumentsJSON != "" {
+				if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+					return "", fmt.Errorf("invalid arguments: %w", err)
+				}
+			}
+
+			root, err := t.resolve(args.Path)
+			if err != nil {
+				return "", err
+			}
+
+			var lines []string
+			var walk func(dir string, depth int) error
+			walk = func(dir string, depth int) error {
+				if depth > maxTreeDepth {
+					return nil
+				}
+				entries, err := os.ReadDir(dir)
+				if err != nil {
+					return fmt.Errorf("failed to read %s: %w", dir, err)
+				}
+				sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+				indent := strings.Repeat("  ", depth-1)
+				for _, entry := range entries {
+					if entry.IsDir() {
+						lines = append(lines, fmt.Sprintf("%s%s/", indent, entry.Name()))
+						if err := walk(filepath.Join(dir, entry.Name()), depth+1); err != nil {
+							return err
+						}
+					} else {
+						lines = append(lines, fmt.Sprintf("%s%s", indent, entry.Name()))
+					}
+				}
+				return nil
+			}
+			if err := walk(root, 1); err != nil {
+				return "", err
+			}
+			return strings.Join(lines, "\n"), nil
+		},
+	}
+}
+
+func (t *Toolbox) readFileSpec() ToolSpec {
+	return ToolSpec{
+		Name:        "read_file",
+		Description: "Read the full contents of a file, relative to the sandbox root.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path": map[string]any{"type": "string", "description": "File to read, relative to the sandbox root."},
+			},
+			"required": []string{"path"},
+		},
+		Impl: func(argumentsJSON string) (string, error) {
+			var args struct {
+				Path string `json:"path"`
+			}
+			if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+
+			full, err := t.resolve(args.Path)
+			if err != nil {
+				return "", err
+			}
+			data, err := os.ReadFile(full)
+			if err != nil {
+				return "", fmt.Errorf("failed to read %s: %w", args.Path, err)
+			}
+			return string(data), nil
+		},
+	}
+}
+
+func (t *Toolbox) writeFileSpec() ToolSpec {
+	return ToolSpec{
+		Name:        "write_file",
+		Description: "Overwrite a file's full contents, relative to the sandbox root. Creates the file if it doesn't exist.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":    map[string]any{"type": "string", "description": "File to write, relative to the sandbox root."},
+				"content": map[string]any{"type": "string", "description": "New full contents of the file."},
+			},
+			"required": []string{"path", "content"},
+		},
+		Impl: func(argumentsJSON string) (string, error) {
+			var args struct {
+				Path    string `json:"path"`
+				Content string `json:"content"`
+			}
+			if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+
+			full, err := t.resolve(args.Path)
+			if err != nil {
+				return "", err
+			}
+			if err := os.WriteFile(full, []byte(args.Content), 0o644); err != nil {
+				return "", fmt.Errorf("failed to write %s: %w", args.Path, err)
+			}
+			return fmt.Sprintf("wrote %d bytes to %s", len(args.Content), args.Path), nil
+		},
+	}
+}
+
+func (t *Toolbox) modifyFileSpec() ToolSpec {
+	return ToolSpec{
+		Name:        "modify_file",
+		Description: "Replace a range of lines (1-indexed, inclusive) in a file with new content, relative to the sandbox root.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"path":       map[string]any{"type": "string", "description": "File to modify, relative to the sandbox root."},
+				"start_line": map[string]any{"type": "integer", "description": "First line to replace (1-indexed, inclusive)."},
+				"end_line":   map[string]any{"type": "integer", "description": "Last line to replace (1-indexed, inclusive)."},
+				"content":    map[string]any{"type": "string", "description": "Text to put in place of those lines."},
+			},
+			"required": []string{"path", "start_line", "end_line", "content"},
+		},
+		Impl: func(argumentsJSON string) (string, error) {
+			var args struct {
+				Path      string `json:"path"`
+				StartLine int    `json:"start_line"`
+				EndLine   int    `json:"end_line"`
+				Content   string `json:"content"`
+			}
+			if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+
+			full, err := t.resolve(args.Path)
+			if err != nil {
+				return "", err
+			}
+
+			data, err := os.ReadFile(full)
+			if err != nil {
+				return "", fmt.Errorf("failed to read %s: %w", args.Path, err)
+			}
+
+			lines := strings.Split(string(data), "\n")
+			if args.StartLine < 1 || args.EndLine < args.StartLine || args.EndLine > len(lines) {
+				return "", fmt.Errorf("line range %d-%d is out of bounds for a %d-line file", args.StartLine, args.EndLine, len(lines))
+			}
+
+			newLines := append([]string{}, lines[:args.StartLine-1]...)
+			newLines = append(newLines, strings.Split(args.Content, "\n")...)
+			newLines = append(newLines, lines[args.EndLine:]...)
+
+			if err := os.WriteFile(full, []byte(strings.Join(newLines, "\n")), 0o644); err != nil {
+				return "", fmt.Errorf("failed to write %s: %w", args.Path, err)
+			}
+			return fmt.Sprintf("replaced lines %d-%d of %s", args.StartLine, args.EndLine, args.Path), nil
+		},
+	}
+}
+
+func (t *Toolbox) shellExecSpec() ToolSpec {
+	return ToolSpec{
+		Name:        "shell_exec",
+		Description: "Run an allow-listed shell command inside the sandbox root and return its combined output.",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"command": map[string]any{"type": "string", "description": "Command name; must be on the allow-list."},
+				"args": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Arguments to pass to command.",
+				},
+			},
+			"required": []string{"command"},
+		},
+		Impl: func(argumentsJSON string) (string, error) {
+			var args struct {
+				Command string   `json:"command"`
+				Args    []string `json:"args"`
+			}
+			if err := json.Unmarshal([]byte(argumentsJSON), &args); err != nil {
+				return "", fmt.Errorf("invalid arguments: %w", err)
+			}
+			if !t.commandAllowed(args.Command) {
+				return "", fmt.Errorf("command %q is not on the shell_exec allow-list", args.Command)
+			}
+
+			cmd := exec.Command(args.Command, args.Args...)
+			cmd.Dir = t.Root
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				return "", fmt.Errorf("%s failed: %w\n%s", args.Command, err, output)
+			}
+			return string(output), nil
+		},
+	}
+}
+
+func (t *Toolbox) commandAllowed(name string) bool {
+	for _, allowed := range t.AllowedCommands {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package toolbox
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolveRejectsAbsolutePath(t *testing.T) {
+	tb := New(t.TempDir())
+
+	if _, err := tb.resolve("/etc/passwd"); err == nil {
+		t.Fatal("resolve of an absolute path: got nil error, want one")
+	}
+}
+
+func TestResolveRejectsParentTraversal(t *testing.T) {
+	tb := New(t.TempDir())
+
+	if _, err := tb.resolve("../escaped"); err == nil {
+		t.Fatal("resolve of a ../ path: got nil error, want one")
+	}
+}
+
+func TestResolveRejectsSiblingPrefix(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "foo")
+	if err := os.Mkdir(root, 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+	sibling := root + "bar"
+	if err := os.Mkdir(sibling, 0o755); err != nil {
+		t.Fatalf("Mkdir: %v", err)
+	}
+
+	tb := New(root)
+	if _, err := tb.resolve("../foobar"); err == nil {
+		t.Fatal("resolve of a sibling-prefix path: got nil error, want one")
+	}
+}
+
+func TestResolveAcceptsPathsWithinRoot(t *testing.T) {
+	root := t.TempDir()
+	tb := New(root)
+
+	full, err := tb.resolve("sub/dir/file.txt")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	want := filepath.Join(root, "sub", "dir", "file.txt")
+	if full != want {
+		t.Fatalf("resolve = %q, want %q", full, want)
+	}
+}
+
+func TestDirTreeStopsAtMaxDepth(t *testing.T) {
+	root := t.TempDir()
+
+	dir := root
+	for i := 0; i < maxTreeDepth+3; i++ {
+		dir = filepath.Join(dir, "d")
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			t.Fatalf("Mkdir: %v", err)
+		}
+	}
+	deepFile := filepath.Join(dir, "deep.txt")
+	if err := os.WriteFile(deepFile, []byte("x"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	tb := New(root)
+	spec := tb.dirTreeSpec()
+	out, err := spec.Impl("")
+	if err != nil {
+		t.Fatalf("dir_tree: %v", err)
+	}
+
+	if strings.Contains(out, "deep.txt") {
+		t.Fatalf("dir_tree output reached past maxTreeDepth (%d): %q", maxTreeDepth, out)
+	}
+
+	if got := strings.Count(out, "d/"); got != maxTreeDepth {
+		t.Fatalf("dir_tree printed %d nested \"d/\" entries, want exactly maxTreeDepth (%d): %q", got, maxTreeDepth, out)
+	}
+}
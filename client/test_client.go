@@ -6,18 +6,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	llamastackclient "github.com/llamastack/llama-stack-client-go"
-	"github.com/llamastack/llama-stack-client-go/option"
 	"github.com/llamastack/llama-stack-client-go/shared"
+
+	"llama-stack-client/pkg/agents"
+	"llama-stack-client/pkg/api"
+	"llama-stack-client/pkg/conversations"
+	"llama-stack-client/pkg/providers"
+	"llama-stack-client/pkg/toolbox"
 )
 
 // ConversationMessage represents a message in the conversation history
 type ConversationMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
-	Type    string `json:"type"`
+	ID       string `json:"id"`
+	ParentID string `json:"parent_id,omitempty"`
+	Role     string `json:"role"`
+	Content  string `json:"content"`
+	Type     string `json:"type"`
 }
 
 // ContentItem represents a single content item in the response
@@ -27,6 +36,37 @@ type ContentItem struct {
 	Annotations []interface{} `json:"annotations"`
 }
 
+// ToolCall describes a tool invocation a response wants to make, before it
+// has been allowed to run. ServerLabel is set for MCP calls awaiting
+// approval and empty for client-executed function calls.
+type ToolCall struct {
+	CallID      string
+	Name        string
+	Arguments   string
+	ServerLabel string
+}
+
+// ToolCallEvent is handed to the registered ToolCallHandler for every
+// pending tool call. The handler must call exactly one of Confirm or Edit
+// before returning so SendMessage knows how to proceed.
+type ToolCallEvent struct {
+	Call ToolCall
+	// Confirm runs (approve) or declines the call as LlamaStack proposed it.
+	Confirm func(approve bool)
+	// Edit approves the call but first rewrites its arguments to argsJSON.
+	// Only client-side function calls run with the rewritten arguments; for
+	// an MCP approval request (ServerLabel set) the rewrite is dropped and
+	// the call is approved with its original arguments.
+	Edit func(argsJSON string)
+}
+
+// ToolCallHandler gates a pending tool call, e.g. by prompting the user.
+type ToolCallHandler func(ToolCallEvent)
+
+// maxToolCallRounds bounds how many times SendMessage will round-trip tool
+// call results back to LlamaStack before giving up on a single turn.
+const maxToolCallRounds = 5
+
 // LlamaStackClient wraps the LlamaStack client for RAG and MCP
 type LlamaStackClient struct {
 	client              *llamastackclient.Client
@@ -35,21 +75,142 @@ type LlamaStackClient struct {
 	sessionID           string
 	vectorStoreID       string
 	mcpToolGroupID      string
+	defaultModel        string
+	agentModel          string
+
+	convStore    *conversations.Store
+	conversation *conversations.Conversation
+
+	agentRegistry *agents.Registry
+	activeAgent   *agents.Agent
+
+	toolCallHandler ToolCallHandler
+	toolExecutor    func(name, argumentsJSON string) (string, error)
+	lastResponseID  string
+
+	stdin *bufio.Reader
+}
+
+// SetToolCallHandler registers the gate every pending tool call is run past
+// before SendMessage lets it execute. Without one, tool calls are declined.
+func (c *LlamaStackClient) SetToolCallHandler(handler ToolCallHandler) {
+	c.toolCallHandler = handler
+}
+
+// SetToolExecutor registers how approved client-side function calls are
+// actually run. Without one, approved function calls report that no
+// executor is registered rather than silently no-op'ing.
+func (c *LlamaStackClient) SetToolExecutor(executor func(name, argumentsJSON string) (string, error)) {
+	c.toolExecutor = executor
+}
+
+// UseToolbox wires tb's built-in tools (dir_tree, read_file, write_file,
+// modify_file, shell_exec) in as the executor for approved function calls,
+// so the assistant can inspect and edit local files (e.g. update
+// eletroshop_history.txt and re-ingest it) without requiring a separate MCP
+// server. Every call is still gated by gateToolCall first, exactly like any
+// other tool call. When an agent (from agents.yaml) is active, each call is
+// also checked against that agent's Tools allow-list, same as
+// effectiveVectorStoreID/effectiveMCPToolGroupID scope RAG and MCP: a tool
+// isn't available just because the process registered it, it also has to be
+// explicitly granted. Outside the agents.yaml flow (no active agent), every
+// registered tool stays available, matching this demo's pre-agents
+// behavior.
+func (c *LlamaStackClient) UseToolbox(tb *toolbox.Toolbox) {
+	specs := make(map[string]toolbox.ToolSpec, len(tb.Specs()))
+	for _, spec := range tb.Specs() {
+		specs[spec.Name] = spec
+	}
+
+	c.toolExecutor = func(name, argumentsJSON string) (string, error) {
+		if c.activeAgent != nil && !c.activeAgent.AllowsTool(name) {
+			return "", fmt.Errorf("agent %q is not granted tool %q", c.activeAgent.Name, name)
+		}
+
+		spec, ok := specs[name]
+		if !ok {
+			return "", fmt.Errorf("no toolbox tool named %q", name)
+		}
+		return spec.Impl(argumentsJSON)
+	}
 }
 
-// NewLlamaStackClient creates a new client configured for Llama Stack
+// NewLlamaStackClient creates a new client configured for Llama Stack,
+// using the demo's historical server URL and models.
 func NewLlamaStackClient() *LlamaStackClient {
-	client := llamastackclient.NewClient(
-		option.WithBaseURL("http://localhost:8321"),
-		option.WithAPIKey("none"),
-	)
+	return NewLlamaStackClientWithConfig(providers.DefaultLlamaStackConfig())
+}
+
+// NewLlamaStackClientWithConfig is NewLlamaStackClient with the LlamaStack
+// server URL, API key, and default models pulled out into cfg, so a caller
+// can point this demo at a different deployment without editing source.
+// --provider/LLM_PROVIDER selection does not go through it.
+func NewLlamaStackClientWithConfig(cfg providers.LlamaStackConfig) *LlamaStackClient {
+	provider := providers.NewLlamaStackProvider(cfg)
 
 	return &LlamaStackClient{
-		client:              &client,
+		client:              provider.Client(),
+		defaultModel:        cfg.DefaultModel,
+		agentModel:          cfg.AgentModel,
 		conversationHistory: make([]ConversationMessage, 0),
 	}
 }
 
+// AttachConversation points the client at a persisted conversation, so that
+// subsequent SendMessage calls append turns to it instead of only keeping
+// them in the in-memory conversationHistory.
+func (c *LlamaStackClient) AttachConversation(store *conversations.Store, conv *conversations.Conversation) {
+	c.convStore = store
+	c.conversation = conv
+}
+
+// Branch starts a new conversation that shares fromMessageID's ancestry but
+// diverges from it, so editing an earlier turn never overwrites history.
+// The client switches to the new branch and returns it.
+func (c *LlamaStackClient) Branch(fromMessageID string) (*conversations.Conversation, error) {
+	if c.convStore == nil || c.conversation == nil {
+		return nil, fmt.Errorf("no attached conversation to branch from")
+	}
+
+	branch, err := c.convStore.Branch(c.conversation, fromMessageID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to branch conversation: %w", err)
+	}
+
+	c.conversation = branch
+	return branch, nil
+}
+
+// LoadAgentRegistry loads the agent config from path into the client so
+// that the /agent command in StartInteractiveChat (and setupClient's
+// -a/--agent flag) can switch between agents by name.
+func (c *LlamaStackClient) LoadAgentRegistry(path string) error {
+	registry, err := agents.LoadRegistry(path)
+	if err != nil {
+		return err
+	}
+	c.agentRegistry = registry
+	return nil
+}
+
+// effectiveVectorStoreID returns the vector store SendMessage should use:
+// the active agent's, if one is set, otherwise whatever setup provisioned.
+func (c *LlamaStackClient) effectiveVectorStoreID() string {
+	if c.activeAgent != nil {
+		return c.activeAgent.PrimaryVectorStoreID()
+	}
+	return c.vectorStoreID
+}
+
+// effectiveMCPToolGroupID returns the MCP toolgroup SendMessage should use:
+// the active agent's, if one is set, otherwise whatever setup provisioned.
+func (c *LlamaStackClient) effectiveMCPToolGroupID() string {
+	if c.activeAgent != nil {
+		return c.activeAgent.PrimaryMCPToolGroupID()
+	}
+	return c.mcpToolGroupID
+}
+
 // ListModels lists all available models
 func (c *LlamaStackClient) ListModels(ctx context.Context) error {
 	fmt.Println("🤖 Listing models...")
@@ -175,6 +336,30 @@ func (c *LlamaStackClient) CreateAgent(ctx context.Context, modelID string) erro
 	return nil
 }
 
+// CreateAgentFromConfig creates the LlamaStack agent using an agents.Agent's
+// model, system prompt, and max_infer_iters, and sets it as the active
+// agent so SendMessage scopes RAG/MCP instructions to what it was granted.
+func (c *LlamaStackClient) CreateAgentFromConfig(ctx context.Context, agent *agents.Agent) error {
+	fmt.Printf("🤖 Creating agent %q...\n", agent.Name)
+
+	agentResponse, err := c.client.Agents.New(ctx, llamastackclient.AgentNewParams{
+		AgentConfig: shared.AgentConfigParam{
+			Model:                    agent.Model,
+			Instructions:             agent.SystemPrompt,
+			EnableSessionPersistence: llamastackclient.Bool(true),
+			MaxInferIters:            llamastackclient.Int(agent.MaxInferIters),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create agent %q: %w", agent.Name, err)
+	}
+
+	c.agentID = agentResponse.AgentID
+	c.activeAgent = agent
+	fmt.Printf("  ✓ Agent created: %s (%s)\n\n", c.agentID, agent.Name)
+	return nil
+}
+
 // CreateSession creates a session for the agent
 func (c *LlamaStackClient) CreateSession(ctx context.Context) error {
 	fmt.Printf("🗣️  Creating agent session...\n")
@@ -191,34 +376,31 @@ func (c *LlamaStackClient) CreateSession(ctx context.Context) error {
 	return nil
 }
 
-// SendMessage sends a message using the Responses API with RAG and MCP support
-func (c *LlamaStackClient) SendMessage(ctx context.Context, message string) (*shared.CompletionMessage, error) {
+// SendMessage sends a message using the Responses API with RAG and MCP
+// support. parentID is the ID of the message this turn replies to (empty for
+// the first turn of a conversation); when a conversation is attached via
+// AttachConversation, both the user turn and the assistant reply are
+// persisted below parentID and the conversation's HeadID advances to the
+// new leaf.
+func (c *LlamaStackClient) SendMessage(ctx context.Context, message string, parentID string) (*shared.CompletionMessage, error) {
+	vectorStoreID := c.effectiveVectorStoreID()
+	mcpToolGroupID := c.effectiveMCPToolGroupID()
+	c.appendUserTurn(parentID, message, vectorStoreID, mcpToolGroupID)
+
 	// Sending message to LlamaStack
 
 	// Build response parameters
 	apiParams := llamastackclient.ResponseNewParams{
-		Model: "ollama/llama3.2:1b",
+		Model: c.defaultModel,
 		Input: llamastackclient.ResponseNewParamsInputUnion{
 			OfString: llamastackclient.String(message),
 		},
 		Store: llamastackclient.Bool(true),
 	}
 
-	// Add instructions based on available tools
-	if c.vectorStoreID != "" {
-
-		apiParams.Instructions = llamastackclient.String("Use the ElectroShop knowledge base to answer questions about company history and information.")
-	}
-
-	if c.mcpToolGroupID != "" {
-
-		if c.vectorStoreID != "" {
-			// Both RAG and MCP available
-			apiParams.Instructions = llamastackclient.String("Use the ElectroShop knowledge base for company information and the sales database tools for customer data operations.")
-		} else {
-			// Only MCP available
-			apiParams.Instructions = llamastackclient.String("Use the ElectroShop sales database tools for customer data operations.")
-		}
+	// Add instructions based on which tools the active agent (if any) grants
+	if instructions := c.instructionsFor(vectorStoreID, mcpToolGroupID); instructions != "" {
+		apiParams.Instructions = llamastackclient.String(instructions)
 	}
 
 	// Note: Tool integration (file_search, MCP) will be added once we determine correct type structures
@@ -236,36 +418,477 @@ func (c *LlamaStackClient) SendMessage(ctx context.Context, message string) (*sh
 		return nil, fmt.Errorf("failed to retrieve response: %w", err)
 	}
 
-	// Extract the actual response content from the full response
-	if len(fullResponse.Output) > 0 {
-		for _, outputItem := range fullResponse.Output {
+	// Gate every pending tool call on confirmation before it (or its result)
+	// goes back to LlamaStack, instead of letting it auto-execute.
+	fullResponse, err = c.resolveToolCalls(ctx, fullResponse)
+	if err != nil {
+		return nil, err
+	}
+	c.lastResponseID = fullResponse.ID
 
-			if outputItem.Type == "message" {
-				msg := outputItem.AsMessage()
+	if responseText := c.extractReplyText(fullResponse); responseText != "" {
+		c.persistReply(responseText)
 
-				// Try to extract the actual AI response text
-				if responseText := c.extractMessageContent(&msg); responseText != "" {
+		return &shared.CompletionMessage{
+			Role: "assistant",
+			Content: shared.InterleavedContentUnion{
+				OfString: responseText,
+			},
+		}, nil
+	}
+
+	// Fallback response
+	fallback := fmt.Sprintf("Response created (ID: %s) but content extraction still needs work.", fullResponse.ID)
+	c.persistReply(fallback)
+	return &shared.CompletionMessage{
+		Role: "assistant",
+		Content: shared.InterleavedContentUnion{
+			OfString: fallback,
+		},
+	}, nil
+}
+
+// defaultPromptStarters are shown when there's no corpus to derive
+// starters from (no vector store attached, or generation failed).
+var defaultPromptStarters = []string{
+	"Tell me about ElectroShop's history",
+	"List all customers in the database",
+	"Add a new customer named John Smith",
+}
 
-					return &shared.CompletionMessage{
-						Role: "assistant",
-						Content: shared.InterleavedContentUnion{
-							OfString: responseText,
-						},
-					}, nil
+// promptStarters picks the example prompts to show at the top of the chat:
+// ones derived from the attached vector store's content if there is one,
+// falling back to defaultPromptStarters otherwise. MCP toolgroups alone
+// don't give GeneratePromptStarters anything to sample, so they also fall
+// back to the defaults.
+func (c *LlamaStackClient) promptStarters(ctx context.Context) []string {
+	vectorStoreID := c.effectiveVectorStoreID()
+	if vectorStoreID == "" {
+		return defaultPromptStarters
+	}
+
+	starters, err := c.GeneratePromptStarters(ctx, vectorStoreID, 3)
+	if err != nil {
+		fmt.Printf("⚠️  Could not generate prompt starters: %v\n", err)
+		return defaultPromptStarters
+	}
+	return starters
+}
+
+// GeneratePromptStarters samples a handful of chunks from vectorStoreID and
+// asks the model to turn them into n short, distinct example questions a
+// user could plausibly ask about that corpus.
+func (c *LlamaStackClient) GeneratePromptStarters(ctx context.Context, vectorStoreID string, n int) ([]string, error) {
+	searchResp, err := c.client.VectorStores.Search(ctx, vectorStoreID, llamastackclient.VectorStoreSearchParams{
+		Query:         llamastackclient.VectorStoreSearchParamsQueryUnion{OfString: llamastackclient.String("overview")},
+		MaxNumResults: llamastackclient.Int(5),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to sample vector store %s: %w", vectorStoreID, err)
+	}
+
+	var sample strings.Builder
+	for _, result := range searchResp.Data {
+		for _, content := range result.Content {
+			sample.WriteString(content.Text)
+			sample.WriteString("\n")
+		}
+	}
+	if sample.Len() == 0 {
+		return nil, fmt.Errorf("vector store %s returned no content to sample", vectorStoreID)
+	}
+
+	prompt := fmt.Sprintf(
+		"Here is an excerpt from a knowledge base:\n\n%s\n\nWrite %d short, distinct example questions a user could plausibly ask about this content. Reply with exactly one question per line and nothing else.",
+		sample.String(), n,
+	)
+
+	response, err := c.client.Responses.New(ctx, llamastackclient.ResponseNewParams{
+		Model: c.defaultModel,
+		Input: llamastackclient.ResponseNewParamsInputUnion{OfString: llamastackclient.String(prompt)},
+		Store: llamastackclient.Bool(false),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate prompt starters: %w", err)
+	}
+
+	fullResponse, err := c.client.Responses.Get(ctx, response.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve prompt starters response: %w", err)
+	}
+
+	starters := make([]string, 0, n)
+	for _, line := range strings.Split(c.extractReplyText(fullResponse), "\n") {
+		if line = stripListMarker(line); line != "" {
+			starters = append(starters, line)
+			if len(starters) == n {
+				break
+			}
+		}
+	}
+	if len(starters) == 0 {
+		return nil, fmt.Errorf("model returned no usable prompt starters")
+	}
+	return starters, nil
+}
+
+// stripListMarker trims a leading "- ", "* ", or "1." / "2)" style list
+// marker from a line the model formatted as a numbered or bulleted list.
+func stripListMarker(line string) string {
+	line = strings.TrimSpace(line)
+	for _, prefix := range []string{"- ", "* ", "• "} {
+		line = strings.TrimPrefix(line, prefix)
+	}
+
+	if idx := strings.IndexAny(line, ".)"); idx > 0 && idx <= 2 {
+		if _, err := strconv.Atoi(strings.TrimSpace(line[:idx])); err == nil {
+			line = strings.TrimSpace(line[idx+1:])
+		}
+	}
+	return line
+}
+
+// extractReplyText returns extractMessageContent's result for the first
+// message item in resp's output. Because extractMessageContent reports an
+// item without readable text with a placeholder string rather than "", this
+// is "" only when resp has no message item at all.
+func (c *LlamaStackClient) extractReplyText(resp *llamastackclient.ResponseObject) string {
+	for _, outputItem := range resp.Output {
+		if outputItem.Type != "message" {
+			continue
+		}
+		msg := outputItem.AsMessage()
+		if responseText := c.extractMessageContent(&msg); responseText != "" {
+			return responseText
+		}
+	}
+	return ""
+}
+
+// resolveToolCalls walks resp's output for pending MCP approval requests and
+// client-side function calls, gates each one through gateToolCall, and
+// round-trips the decisions back to LlamaStack until a round produces no
+// further tool calls (or maxToolCallRounds is hit).
+func (c *LlamaStackClient) resolveToolCalls(ctx context.Context, resp *llamastackclient.ResponseObject) (*llamastackclient.ResponseObject, error) {
+	current := resp
+
+	for round := 0; round < maxToolCallRounds; round++ {
+		var results []llamastackclient.ResponseNewParamsInputArrayItemUnion
+
+		for _, outputItem := range current.Output {
+			switch outputItem.Type {
+			case "mcp_approval_request":
+				req := outputItem.AsMcpApprovalRequest()
+				approved, _ := c.gateToolCall(ToolCall{
+					CallID:      req.ID,
+					Name:        req.Name,
+					Arguments:   req.Arguments,
+					ServerLabel: req.ServerLabel,
+				})
+				results = append(results, llamastackclient.ResponseNewParamsInputArrayItemUnion{
+					OfOpenAIResponseMcpApprovalResponse: &llamastackclient.ResponseNewParamsInputArrayItemOpenAIResponseMcpApprovalResponse{
+						ApprovalRequestID: req.ID,
+						Approve:           approved,
+					},
+				})
+
+			case "function_call":
+				call := outputItem.AsFunctionCall()
+				approved, argsJSON := c.gateToolCall(ToolCall{
+					CallID:    call.CallID,
+					Name:      call.Name,
+					Arguments: call.Arguments,
+				})
+
+				output := `{"error":"tool call declined by user"}`
+				if approved {
+					output = c.executeTool(call.Name, argsJSON)
 				}
+				results = append(results, llamastackclient.ResponseNewParamsInputArrayItemUnion{
+					OfOpenAIResponseInputFunctionToolCallOutput: &llamastackclient.ResponseNewParamsInputArrayItemOpenAIResponseInputFunctionToolCallOutput{
+						CallID: call.CallID,
+						Output: output,
+					},
+				})
 			}
 		}
+
+		if len(results) == 0 {
+			return current, nil
+		}
+
+		followUp, err := c.client.Responses.New(ctx, llamastackclient.ResponseNewParams{
+			Model:              c.defaultModel,
+			PreviousResponseID: llamastackclient.String(current.ID),
+			Input:              llamastackclient.ResponseNewParamsInputUnion{OfResponseNewsInputArray: results},
+			Store:              llamastackclient.Bool(true),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to submit tool call results: %w", err)
+		}
+
+		current, err = c.client.Responses.Get(ctx, followUp.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve response after tool calls: %w", err)
+		}
 	}
 
-	// Fallback response
+	for _, outputItem := range current.Output {
+		if outputItem.Type == "mcp_approval_request" || outputItem.Type == "function_call" {
+			return nil, fmt.Errorf("exceeded %d rounds of tool calls without a final answer", maxToolCallRounds)
+		}
+	}
+	return current, nil
+}
+
+// gateToolCall runs call past the registered ToolCallHandler and blocks
+// until it decides. Declines by default when no handler is registered.
+func (c *LlamaStackClient) gateToolCall(call ToolCall) (approved bool, argumentsJSON string) {
+	argumentsJSON = call.Arguments
+
+	if c.toolCallHandler == nil {
+		fmt.Printf("⚠️  Declining tool call %s: no confirmation handler registered\n", call.Name)
+		return false, argumentsJSON
+	}
+
+	done := make(chan struct{})
+	event := ToolCallEvent{Call: call}
+	event.Confirm = func(approve bool) {
+		approved = approve
+		close(done)
+	}
+	event.Edit = func(newArgumentsJSON string) {
+		approved = true
+		argumentsJSON = newArgumentsJSON
+		close(done)
+	}
+
+	c.toolCallHandler(event)
+	<-done
+	return approved, argumentsJSON
+}
+
+// executeTool runs an approved client-side function call through the
+// registered executor, returning its result (or an error payload) as the
+// JSON string LlamaStack expects as the tool's output.
+func (c *LlamaStackClient) executeTool(name, argumentsJSON string) string {
+	if c.toolExecutor == nil {
+		return fmt.Sprintf("{\"error\":\"no executor registered for tool %s\"}", name)
+	}
+
+	result, err := c.toolExecutor(name, argumentsJSON)
+	if err != nil {
+		encodedErr, _ := json.Marshal(err.Error())
+		return fmt.Sprintf("{\"error\":%s}", encodedErr)
+	}
+	return result
+}
+
+// SubmitToolResult manually round-trips a tool result into the conversation
+// that produced callID, for cases where the result was computed outside the
+// SendMessage/ToolCallHandler flow (e.g. a slow or externally-confirmed
+// tool). It resolves any further tool calls the continuation triggers.
+func (c *LlamaStackClient) SubmitToolResult(ctx context.Context, callID, resultJSON string) (*shared.CompletionMessage, error) {
+	if c.lastResponseID == "" {
+		return nil, fmt.Errorf("no active response to submit a tool result for")
+	}
+
+	followUp, err := c.client.Responses.New(ctx, llamastackclient.ResponseNewParams{
+		Model:              c.defaultModel,
+		PreviousResponseID: llamastackclient.String(c.lastResponseID),
+		Input: llamastackclient.ResponseNewParamsInputUnion{
+			OfResponseNewsInputArray: []llamastackclient.ResponseNewParamsInputArrayItemUnion{{
+				OfOpenAIResponseInputFunctionToolCallOutput: &llamastackclient.ResponseNewParamsInputArrayItemOpenAIResponseInputFunctionToolCallOutput{
+					CallID: callID,
+					Output: resultJSON,
+				},
+			}},
+		},
+		Store: llamastackclient.Bool(true),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit tool result: %w", err)
+	}
+
+	fullResponse, err := c.client.Responses.Get(ctx, followUp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve response: %w", err)
+	}
+
+	fullResponse, err = c.resolveToolCalls(ctx, fullResponse)
+	if err != nil {
+		return nil, err
+	}
+	c.lastResponseID = fullResponse.ID
+
+	responseText := c.extractReplyText(fullResponse)
+	if responseText == "" {
+		responseText = fmt.Sprintf("Response created (ID: %s) but content extraction still needs work.", fullResponse.ID)
+	}
+	c.persistReply(responseText)
+
 	return &shared.CompletionMessage{
 		Role: "assistant",
 		Content: shared.InterleavedContentUnion{
-			OfString: fmt.Sprintf("Response created (ID: %s) but content extraction still needs work.", response.ID),
+			OfString: responseText,
 		},
 	}, nil
 }
 
+// appendUserTurn records the user's side of a turn below parentID, if a
+// conversation is attached.
+func (c *LlamaStackClient) appendUserTurn(parentID, message, vectorStoreID, mcpToolGroupID string) {
+	if c.conversation == nil {
+		return
+	}
+	c.conversation.AppendMessage(parentID, conversations.Message{
+		Role:           "user",
+		Content:        message,
+		AgentID:        c.agentID,
+		SessionID:      c.sessionID,
+		VectorStoreID:  vectorStoreID,
+		MCPToolGroupID: mcpToolGroupID,
+	})
+}
+
+// instructionsFor returns the system instructions to send for a turn given
+// which tools (if any) are in scope, or "" if neither is available.
+func (c *LlamaStackClient) instructionsFor(vectorStoreID, mcpToolGroupID string) string {
+	switch {
+	case vectorStoreID != "" && mcpToolGroupID != "":
+		return "Use the ElectroShop knowledge base for company information and the sales database tools for customer data operations."
+	case vectorStoreID != "":
+		return "Use the ElectroShop knowledge base to answer questions about company history and information."
+	case mcpToolGroupID != "":
+		return "Use the ElectroShop sales database tools for customer data operations."
+	default:
+		return ""
+	}
+}
+
+// StreamChunk is one incremental piece of a streamed response. DeltaText and
+// ToolCallDelta are mutually exclusive per chunk; PromptTokens,
+// CompletionTokens, and FinishReason are only set on the final chunk.
+type StreamChunk struct {
+	DeltaText        string
+	ToolCallDelta    string
+	PromptTokens     int64
+	CompletionTokens int64
+	FinishReason     string
+}
+
+// SendMessageStream is the streaming counterpart to SendMessage: it consumes
+// the Responses API in SSE mode and yields StreamChunk values as the reply
+// is generated, instead of blocking until the whole reply is ready. The
+// final chunk carries token counts and FinishReason, and the full text is
+// persisted to the attached conversation at that point. Streamed tool call
+// deltas are surfaced via ToolCallDelta for display only; once the response
+// completes, its pending tool calls are gated and executed through
+// resolveToolCalls, exactly as on the non-streaming path.
+func (c *LlamaStackClient) SendMessageStream(ctx context.Context, message string, parentID string) (<-chan StreamChunk, error) {
+	vectorStoreID := c.effectiveVectorStoreID()
+	mcpToolGroupID := c.effectiveMCPToolGroupID()
+	c.appendUserTurn(parentID, message, vectorStoreID, mcpToolGroupID)
+
+	apiParams := llamastackclient.ResponseNewParams{
+		Model: c.defaultModel,
+		Input: llamastackclient.ResponseNewParamsInputUnion{
+			OfString: llamastackclient.String(message),
+		},
+		Store: llamastackclient.Bool(true),
+	}
+	if instructions := c.instructionsFor(vectorStoreID, mcpToolGroupID); instructions != "" {
+		apiParams.Instructions = llamastackclient.String(instructions)
+	}
+
+	stream := c.client.Responses.NewStreaming(ctx, apiParams)
+	chunks := make(chan StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer stream.Close()
+
+		var fullText strings.Builder
+		for stream.Next() {
+			event := stream.Current()
+			switch event.Type {
+			case "response.output_text.delta":
+				fullText.WriteString(event.Delta)
+				chunks <- StreamChunk{DeltaText: event.Delta}
+
+			case "response.function_call_arguments.delta", "response.mcp_call.arguments.delta":
+				chunks <- StreamChunk{ToolCallDelta: event.Delta}
+
+			case "response.completed":
+				full, err := c.client.Responses.Get(ctx, event.Response.ID)
+				if err != nil {
+					chunks <- StreamChunk{FinishReason: fmt.Sprintf("error: %v", err)}
+					return
+				}
+
+				// Gate and execute any pending tool calls exactly as
+				// SendMessage does, instead of leaving them unresolved:
+				// otherwise a turn that triggers an MCP or toolbox call
+				// would stream nothing further and never complete.
+				full, err = c.resolveToolCalls(ctx, full)
+				if err != nil {
+					chunks <- StreamChunk{FinishReason: fmt.Sprintf("error: %v", err)}
+					return
+				}
+				c.lastResponseID = full.ID
+
+				finalText := c.extractReplyText(full)
+				if finalText == "" {
+					finalText = fullText.String()
+				} else if extra := strings.TrimPrefix(finalText, fullText.String()); extra != "" {
+					// Text produced while resolving tool calls wasn't
+					// streamed yet; deliver it as one last delta.
+					chunks <- StreamChunk{DeltaText: extra}
+				}
+				c.persistReply(finalText)
+
+				chunks <- StreamChunk{
+					PromptTokens:     full.Usage.InputTokens,
+					CompletionTokens: full.Usage.OutputTokens,
+					FinishReason:     "stop",
+				}
+
+			case "response.failed", "response.incomplete":
+				chunks <- StreamChunk{FinishReason: event.Type}
+			}
+		}
+
+		if err := stream.Err(); err != nil {
+			chunks <- StreamChunk{FinishReason: fmt.Sprintf("error: %v", err)}
+		}
+	}()
+
+	return chunks, nil
+}
+
+// persistReply appends the assistant's reply below the current conversation
+// head (the user turn SendMessage just recorded) and saves to the store.
+func (c *LlamaStackClient) persistReply(text string) {
+	if c.conversation == nil {
+		return
+	}
+
+	c.conversation.AppendMessage(c.conversation.HeadID, conversations.Message{
+		Role:           "assistant",
+		Content:        text,
+		AgentID:        c.agentID,
+		SessionID:      c.sessionID,
+		VectorStoreID:  c.effectiveVectorStoreID(),
+		MCPToolGroupID: c.effectiveMCPToolGroupID(),
+	})
+
+	if c.convStore != nil {
+		if err := c.convStore.Save(c.conversation); err != nil {
+			fmt.Printf("⚠️  Warning: failed to save conversation: %v\n", err)
+		}
+	}
+}
+
 // extractMessageContent tries to extract text content from a response message
 func (c *LlamaStackClient) extractMessageContent(msg *llamastackclient.ResponseObjectOutputMessage) string {
 	// Parse the content JSON to extract the actual text
@@ -300,25 +923,54 @@ func min(a, b int) int {
 	return b
 }
 
+// promptToolCallConfirmation is the default ToolCallHandler used by
+// StartInteractiveChat: it asks the user before any tool call is allowed to
+// run, and lets them rewrite its arguments instead of just approving them.
+func (c *LlamaStackClient) promptToolCallConfirmation(event ToolCallEvent) {
+	fmt.Printf("\n🛠️  Run tool %s(%s)? [y/e/N]: ", event.Call.Name, event.Call.Arguments)
+
+	line, _ := c.stdin.ReadString('\n')
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		event.Confirm(true)
+	case "e", "edit":
+		fmt.Print("New arguments (JSON): ")
+		argsLine, _ := c.stdin.ReadString('\n')
+		event.Edit(strings.TrimSpace(argsLine))
+	default:
+		event.Confirm(false)
+	}
+}
+
 // StartInteractiveChat starts an interactive chat session
 func (c *LlamaStackClient) StartInteractiveChat(ctx context.Context) error {
 	fmt.Println("🎉 Starting interactive chat with RAG + MCP support!")
 	fmt.Println("Type 'exit' to quit, 'clear' to clear conversation history")
+	if c.conversation != nil {
+		fmt.Printf("Conversation: %s (type '/branch <messageID>' to edit and re-prompt from an earlier turn)\n", c.conversation.ID)
+	}
+	if c.agentRegistry != nil {
+		fmt.Printf("Agents available: %v (type '/agent <name>' to switch)\n", c.agentRegistry.Names())
+	}
 	fmt.Println("Examples:")
-	fmt.Println("- Tell me about ElectroShop's history")
-	fmt.Println("- List all customers in the database")
-	fmt.Println("- Add a new customer named John Smith")
+	for _, starter := range c.promptStarters(ctx) {
+		fmt.Printf("- %s\n", starter)
+	}
 	fmt.Println("=====================================")
 
-	scanner := bufio.NewScanner(os.Stdin)
+	c.stdin = bufio.NewReader(os.Stdin)
+	if c.toolCallHandler == nil {
+		c.toolCallHandler = c.promptToolCallConfirmation
+	}
 
 	for {
 		fmt.Print("\n🗨️  You: ")
-		if !scanner.Scan() {
+		line, err := c.stdin.ReadString('\n')
+		if err != nil {
 			break
 		}
 
-		input := strings.TrimSpace(scanner.Text())
+		input := strings.TrimSpace(line)
 		if input == "" {
 			continue
 		}
@@ -334,23 +986,73 @@ func (c *LlamaStackClient) StartInteractiveChat(ctx context.Context) error {
 			continue
 		}
 
-		// Send message and get response
-		response, err := c.SendMessage(ctx, input)
+		if strings.HasPrefix(input, "/branch ") {
+			fromMessageID := strings.TrimSpace(strings.TrimPrefix(input, "/branch "))
+			branch, err := c.Branch(fromMessageID)
+			if err != nil {
+				fmt.Printf("❌ Error: %v\n", err)
+				continue
+			}
+			fmt.Printf("🌿 Switched to new branch %s from message %s\n", branch.ID, fromMessageID)
+			continue
+		}
+
+		if strings.HasPrefix(input, "/agent ") {
+			if c.agentRegistry == nil {
+				fmt.Printf("❌ Error: no agent config loaded (create ~/.config/llamastack-demo/agents.yaml)\n")
+				continue
+			}
+			name := strings.TrimSpace(strings.TrimPrefix(input, "/agent "))
+			agent, ok := c.agentRegistry.Get(name)
+			if !ok {
+				fmt.Printf("❌ Error: unknown agent %q (known: %v)\n", name, c.agentRegistry.Names())
+				continue
+			}
+			if err := c.CreateAgentFromConfig(ctx, agent); err != nil {
+				fmt.Printf("❌ Error switching agent: %v\n", err)
+				continue
+			}
+			if err := c.CreateSession(ctx); err != nil {
+				fmt.Printf("❌ Error creating session for agent %q: %v\n", name, err)
+				continue
+			}
+			fmt.Printf("🔀 Switched to agent %q\n", name)
+			continue
+		}
+
+		parentID := ""
+		if c.conversation != nil {
+			parentID = c.conversation.HeadID
+		}
+
+		// Send message and render the reply as it streams in
+		started := time.Now()
+		chunks, err := c.SendMessageStream(ctx, input, parentID)
 		if err != nil {
 			fmt.Printf("❌ Error: %v\n", err)
 			continue
 		}
 
-		// Extract and display response content
 		fmt.Print("🤖 Assistant: ")
-		if response.Content.OfString != "" {
-			fmt.Printf("%s\n", response.Content.OfString)
-		} else if response.ToolCalls != nil {
-			fmt.Printf("🛠️  Executing tools...\n")
-			// Tool execution details would be shown here
-		} else {
-			fmt.Printf("(No text response available)\n")
+		var fullText strings.Builder
+		var promptTokens, completionTokens int64
+		for chunk := range chunks {
+			if chunk.DeltaText != "" {
+				fmt.Print(chunk.DeltaText)
+				fullText.WriteString(chunk.DeltaText)
+			}
+			if chunk.FinishReason != "" {
+				promptTokens = chunk.PromptTokens
+				completionTokens = chunk.CompletionTokens
+				if chunk.FinishReason != "stop" {
+					fmt.Printf("\n⚠️  %s\n", chunk.FinishReason)
+				}
+			}
 		}
+		if fullText.Len() == 0 {
+			fmt.Print("(No text response available)")
+		}
+		fmt.Printf("\n[%d↑ %d↓ %.1fs]\n", promptTokens, completionTokens, time.Since(started).Seconds())
 
 		// Add to conversation history
 		c.conversationHistory = append(c.conversationHistory, ConversationMessage{
@@ -359,10 +1061,10 @@ func (c *LlamaStackClient) StartInteractiveChat(ctx context.Context) error {
 			Type:    "message",
 		})
 
-		if response.Content.OfString != "" {
+		if fullText.Len() > 0 {
 			c.conversationHistory = append(c.conversationHistory, ConversationMessage{
 				Role:    "assistant",
-				Content: response.Content.OfString,
+				Content: fullText.String(),
 				Type:    "message",
 			})
 		}
@@ -371,70 +1073,232 @@ func (c *LlamaStackClient) StartInteractiveChat(ctx context.Context) error {
 	return nil
 }
 
-func main() {
-	fmt.Println("🚀 LlamaStack Go Client - RAG + MCP Demo")
-	fmt.Println("=========================================")
-
-	ctx := context.Background()
+// setupClient runs the RAG + MCP bootstrap and returns a client ready for
+// StartInteractiveChat. If agentName is non-empty, the named agent from
+// agents.yaml is used instead of the always-on RAG+MCP wiring: only the
+// vector stores and MCP toolgroups that agent was explicitly granted are
+// used. That path provisions nothing itself: it creates no vector store and
+// registers no MCP toolgroup, so the granted IDs must already exist.
+func setupClient(ctx context.Context, agentName string) (*LlamaStackClient, error) {
 	client := NewLlamaStackClient()
 
-	// Test 1: List available models
 	fmt.Printf("🔍 Step 1: Listing available models...\n")
 	if err := client.ListModels(ctx); err != nil {
-		fmt.Printf("❌ Error listing models: %v\n", err)
-		return
+		return nil, fmt.Errorf("error listing models: %w", err)
 	}
 
-	selectedModel := "ollama/llama3.2:3b" // Use available model
+	registryPath, err := agents.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := client.LoadAgentRegistry(registryPath); err != nil {
+		return nil, fmt.Errorf("error loading agent config: %w", err)
+	}
+
+	if agentName != "" {
+		agent, ok := client.agentRegistry.Get(agentName)
+		if !ok {
+			return nil, fmt.Errorf("unknown agent %q (known: %v)", agentName, client.agentRegistry.Names())
+		}
+
+		if agent.PrimaryMCPToolGroupID() != "" {
+			fmt.Printf("🛠️  Using MCP toolgroup granted to agent %q: %s\n", agentName, agent.PrimaryMCPToolGroupID())
+		}
+
+		fmt.Printf("🧰 Step 2: Setting up local filesystem toolbox...\n")
+		client.UseToolbox(toolbox.New("."))
+
+		fmt.Printf("🤖 Step 3: Creating agent %q...\n", agentName)
+		if err := client.CreateAgentFromConfig(ctx, agent); err != nil {
+			return nil, fmt.Errorf("error creating agent: %w", err)
+		}
+
+		fmt.Printf("🗣️  Step 4: Creating agent session...\n")
+		if err := client.CreateSession(ctx); err != nil {
+			return nil, fmt.Errorf("error creating session: %w", err)
+		}
+
+		return client, nil
+	}
+
+	selectedModel := client.agentModel
 
-	// Test 2: Setup RAG - Create vector store and upload file
 	fmt.Printf("📚 Step 2: Setting up RAG system...\n")
 	vectorStore, err := client.CreateVectorStore(ctx, "ElectroShop Knowledge Base")
 	if err != nil {
-		fmt.Printf("❌ Error creating vector store: %v\n", err)
-		return
+		return nil, fmt.Errorf("error creating vector store: %w", err)
 	}
 
-	// Upload ElectroShop history file
 	testFile := "eletroshop_history.txt"
 	if _, statErr := os.Stat(testFile); statErr == nil {
 		fileID, uploadErr := client.UploadFile(ctx, testFile)
 		if uploadErr != nil {
-			fmt.Printf("❌ Error uploading file: %v\n", uploadErr)
-			return
+			return nil, fmt.Errorf("error uploading file: %w", uploadErr)
 		}
 
 		if addErr := client.AddFileToVectorStore(ctx, vectorStore.ID, fileID); addErr != nil {
-			fmt.Printf("❌ Error adding file to vector store: %v\n", addErr)
-			return
+			return nil, fmt.Errorf("error adding file to vector store: %w", addErr)
 		}
 	} else {
 		fmt.Printf("⚠️  File %s not found, continuing without RAG data\n", testFile)
 	}
 
-	// Test 3: Setup MCP tool group
 	fmt.Printf("🛠️  Step 3: Setting up MCP integration...\n")
 	if err := client.SetupMCPToolGroup(ctx); err != nil {
 		fmt.Printf("❌ Error setting up MCP: %v\n", err)
 		fmt.Printf("⚠️  Continuing without MCP tools (make sure MCP server is running at http://127.0.0.1:8000/mcp)\n")
 	}
 
-	// Test 4: Create agent for conversation management
-	fmt.Printf("🤖 Step 4: Creating conversational agent...\n")
+	fmt.Printf("🧰 Step 4: Setting up local filesystem toolbox...\n")
+	client.UseToolbox(toolbox.New("."))
+
+	fmt.Printf("🤖 Step 5: Creating conversational agent...\n")
 	if err := client.CreateAgent(ctx, selectedModel); err != nil {
-		fmt.Printf("❌ Error creating agent: %v\n", err)
-		return
+		return nil, fmt.Errorf("error creating agent: %w", err)
 	}
 
-	// Test 5: Create session for the agent
-	fmt.Printf("🗣️  Step 5: Creating agent session...\n")
+	fmt.Printf("🗣️  Step 6: Creating agent session...\n")
 	if err := client.CreateSession(ctx); err != nil {
-		fmt.Printf("❌ Error creating session: %v\n", err)
+		return nil, fmt.Errorf("error creating session: %w", err)
+	}
+
+	return client, nil
+}
+
+// openConvStore opens the default conversation store, creating it on disk if
+// this is the first time it's used.
+func openConvStore() (*conversations.Store, error) {
+	dir, err := conversations.DefaultDir()
+	if err != nil {
+		return nil, err
+	}
+	return conversations.NewStore(dir)
+}
+
+// cmdNew bootstraps a fresh RAG + MCP session, persists it as a new
+// conversation, and drops the user into the interactive chat loop.
+func cmdNew(ctx context.Context, title string, agentName string) error {
+	store, err := openConvStore()
+	if err != nil {
+		return err
+	}
+
+	client, err := setupClient(ctx, agentName)
+	if err != nil {
+		return err
+	}
+
+	conv, err := store.New(title)
+	if err != nil {
+		return err
+	}
+	client.AttachConversation(store, conv)
+
+	fmt.Printf("🆕 Created conversation %s\n", conv.ID)
+	fmt.Printf("💬 Step 7: Starting interactive chat...\n")
+	return client.StartInteractiveChat(ctx)
+}
+
+// cmdReply re-bootstraps RAG + MCP (server-side agents/sessions don't
+// outlive the demo process) and resumes an existing conversation's active
+// branch, appending new turns below its current head.
+func cmdReply(ctx context.Context, id string, agentName string) error {
+	store, err := openConvStore()
+	if err != nil {
+		return err
+	}
+
+	conv, err := store.Load(id)
+	if err != nil {
+		return err
+	}
+
+	client, err := setupClient(ctx, agentName)
+	if err != nil {
+		return err
+	}
+	client.AttachConversation(store, conv)
+
+	fmt.Printf("↩️  Resuming conversation %s (%d turns so far)\n", conv.ID, len(conv.History()))
+	fmt.Printf("💬 Step 7: Starting interactive chat...\n")
+	return client.StartInteractiveChat(ctx)
+}
+
+// cmdView prints a conversation's active branch, root to leaf.
+func cmdView(id string) error {
+	store, err := openConvStore()
+	if err != nil {
+		return err
+	}
+
+	conv, err := store.Load(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📖 Conversation %s%s\n", conv.ID, titleSuffix(conv.Title))
+	for _, msg := range conv.History() {
+		fmt.Printf("[%s] %s: %s\n", msg.ID, msg.Role, msg.Content)
+	}
+	return nil
+}
+
+// cmdLs lists every stored conversation, most recent first.
+func cmdLs() error {
+	store, err := openConvStore()
+	if err != nil {
+		return err
+	}
+
+	convs, err := store.List()
+	if err != nil {
+		return err
+	}
+
+	if len(convs) == 0 {
+		fmt.Println("No conversations yet. Start one with: new")
+		return nil
+	}
+
+	for _, conv := range convs {
+		fmt.Printf("%s  %-20s  %d turns  %s\n", conv.ID, conv.CreatedAt.Format("2006-01-02 15:04"), len(conv.Messages), conv.Title)
+	}
+	return nil
+}
+
+// cmdRm permanently deletes a conversation.
+func cmdRm(id string) error {
+	store, err := openConvStore()
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(id); err != nil {
+		return err
+	}
+	fmt.Printf("🗑️  Deleted conversation %s\n", id)
+	return nil
+}
+
+func titleSuffix(title string) string {
+	if title == "" {
+		return ""
+	}
+	return " - " + title
+}
+
+// runDemo runs the original one-shot demo flow: bootstrap RAG + MCP, then
+// drop into an interactive chat without any conversation persistence.
+func runDemo(ctx context.Context, agentName string) {
+	fmt.Println("🚀 LlamaStack Go Client - RAG + MCP Demo")
+	fmt.Println("=========================================")
+
+	client, err := setupClient(ctx, agentName)
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
 		return
 	}
 
-	// Test 6: Start interactive chat
-	fmt.Printf("💬 Step 6: Starting interactive chat...\n")
+	fmt.Printf("💬 Step 7: Starting interactive chat...\n")
 	if err := client.StartInteractiveChat(ctx); err != nil {
 		fmt.Printf("❌ Error in chat: %v\n", err)
 		return
@@ -442,3 +1306,116 @@ func main() {
 
 	fmt.Println("🎉 Demo completed successfully!")
 }
+
+// parseAgentFlag pulls a "-a <name>" / "--agent <name>" pair out of args,
+// returning the agent name (empty if absent) and the remaining args.
+func parseAgentFlag(args []string) (string, []string) {
+	agentName := ""
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "-a" || args[i] == "--agent") && i+1 < len(args) {
+			agentName = args[i+1]
+			i++
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	return agentName, rest
+}
+
+// parseProviderFlag pulls a "--provider <name>" pair out of args, falling
+// back to the LLM_PROVIDER environment variable if the flag isn't given.
+// "" (the default) and "llamastack" both mean the always-on RAG+MCP backend;
+// any other name is looked up in newProvider.
+func parseProviderFlag(args []string) (string, []string) {
+	providerName := os.Getenv("LLM_PROVIDER")
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--provider" && i+1 < len(args) {
+			providerName = args[i+1]
+			i++
+			continue
+		}
+		rest = append(rest, args[i])
+	}
+	return providerName, rest
+}
+
+// newProvider builds the api.ChatProvider named by name, for cmdModels.
+// This is the only place in the demo that calls chat flows through the
+// api.ChatProvider interface at all: LlamaStackClient's own SendMessage,
+// SendMessageStream, RAG, MCP, and toolbox flows call the concrete
+// llamastackclient SDK directly rather than going through
+// providers.LlamaStackProvider (see the doc comment on package api for
+// why), so picking --provider/LLM_PROVIDER does not change what backend a
+// chat actually runs against today. "openai"/"anthropic"/"gemini" are
+// scaffolding on top of that: every api.ChatProvider method they
+// implement, including ListModels, returns a clear "not wired up" error.
+// See pkg/providers/stub.go.
+func newProvider(name string) (api.ChatProvider, error) {
+	switch name {
+	case "", "llamastack":
+		return providers.NewLlamaStackProvider(providers.DefaultLlamaStackConfig()), nil
+	case "openai":
+		return providers.NewOpenAIProvider(providers.StubConfig{APIKey: os.Getenv("OPENAI_API_KEY"), Model: os.Getenv("OPENAI_MODEL")}), nil
+	case "anthropic":
+		return providers.NewAnthropicProvider(providers.StubConfig{APIKey: os.Getenv("ANTHROPIC_API_KEY"), Model: os.Getenv("ANTHROPIC_MODEL")}), nil
+	case "gemini":
+		return providers.NewGeminiProvider(providers.StubConfig{APIKey: os.Getenv("GOOGLE_API_KEY"), Model: os.Getenv("GEMINI_MODEL")}), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q (known: llamastack, openai, anthropic, gemini)", name)
+	}
+}
+
+// cmdModels lists the models the selected provider can serve, so a
+// --provider/LLM_PROVIDER choice can be sanity-checked before it's used for
+// a chat.
+func cmdModels(ctx context.Context, providerName string) error {
+	provider, err := newProvider(providerName)
+	if err != nil {
+		return err
+	}
+
+	models, err := provider.ListModels(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list models: %w", err)
+	}
+
+	for _, model := range models {
+		fmt.Printf("  ✓ %s\n", model)
+	}
+	return nil
+}
+
+func main() {
+	ctx := context.Background()
+	agentName, args := parseAgentFlag(os.Args[1:])
+	providerName, args := parseProviderFlag(args)
+
+	var err error
+	switch {
+	case len(args) == 0:
+		runDemo(ctx, agentName)
+		return
+	case args[0] == "models":
+		err = cmdModels(ctx, providerName)
+	case args[0] == "new":
+		err = cmdNew(ctx, strings.Join(args[1:], " "), agentName)
+	case args[0] == "reply" && len(args) > 1:
+		err = cmdReply(ctx, args[1], agentName)
+	case args[0] == "view" && len(args) > 1:
+		err = cmdView(args[1])
+	case args[0] == "ls":
+		err = cmdLs()
+	case args[0] == "rm" && len(args) > 1:
+		err = cmdRm(args[1])
+	default:
+		fmt.Printf("Usage: %s [-a|--agent <name>] [--provider <name>] [new [title] | reply <id> | view <id> | ls | rm <id> | models]\n", os.Args[0])
+		return
+	}
+
+	if err != nil {
+		fmt.Printf("❌ Error: %v\n", err)
+		os.Exit(1)
+	}
+}